@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeTestPayload struct {
+	Name string `json:"name"`
+}
+
+func TestDecodeLimitRejectsOversizedBody(t *testing.T) {
+	body := []byte(`{"name":"` + strings.Repeat("x", 100) + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	var dst decodeTestPayload
+	err := DecodeLimit(req, &dst, 10)
+
+	apiErr, ok := err.(ApiError)
+	if !ok {
+		t.Fatalf("err = %v (%T), want ApiError", err, err)
+	}
+	if apiErr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestDecodeUsesDefaultLimit(t *testing.T) {
+	body := []byte(`{"name":"ok"}`)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	var dst decodeTestPayload
+	if err := Decode(req, &dst); err != nil {
+		t.Fatalf("Decode() = %v, want nil", err)
+	}
+	if dst.Name != "ok" {
+		t.Fatalf("Name = %q, want %q", dst.Name, "ok")
+	}
+}
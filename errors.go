@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ApiError представляет ошибки бизнес-логики
+type ApiError struct {
+	StatusCode int `json:"statusCode"`
+	Msg        any `json:"msg"`
+}
+
+func (e ApiError) Error() string {
+	return fmt.Sprintf("%d: %v", e.StatusCode, e.Msg)
+}
+
+func NewApiError(statusCode int, err error) ApiError {
+	return ApiError{
+		StatusCode: statusCode,
+		Msg:        err.Error(),
+	}
+}
+
+// InfraError представляет ошибки инфраструктуры
+type InfraError struct {
+	ServiceName string
+	Msg         string
+}
+
+func (e InfraError) Error() string {
+	return fmt.Sprintf("infrastructure error with service %s: %s", e.ServiceName, e.Msg)
+}
+
+func NewInfraError(serviceName, msg string) InfraError {
+	return InfraError{
+		ServiceName: serviceName,
+		Msg:         msg,
+	}
+}
+
+// ProblemError - ошибка в формате problem+json (RFC 7807), с расширением
+// Code/Fields/Request: Code - код ошибки бизнес-логики (сейчас WriteError его
+// не заполняет, свободен для обработчиков), Fields - ошибки валидации по
+// полям, Request - идентификатор запроса, тот же, что и errResponse.Request.
+type ProblemError struct {
+	Type     string            `json:"type,omitempty"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Request  string            `json:"request,omitempty"`
+}
+
+func (e ProblemError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Status, e.Title)
+}
+
+// errResponse - конверт ошибки в старом, "плоском" формате, дополненный
+// идентификатором запроса.
+type errResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Msg        any    `json:"msg"`
+	Request    string `json:"request,omitempty"`
+}
+
+// APIFunc - обработчик API, который может вернуть ошибку
+type APIFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Make оборачивает APIFunc для обработки ошибок
+func Make(h APIFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			WriteError(w, r, err)
+		}
+	}
+}
+
+// WriteError записывает err в ответ в подходящем формате (problem+json или
+// старый плоский конверт, в зависимости от Accept) и логирует инфраструктурные
+// и неизвестные ошибки. Позволяет обработчикам завершать запрос с ошибкой,
+// не возвращая её из APIFunc.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	requestID := RequestIDFromContext(r.Context())
+
+	var problem ProblemError
+	switch e := err.(type) {
+	case ProblemError:
+		problem = e
+	case ApiError:
+		problem = ProblemError{Title: http.StatusText(e.StatusCode), Status: e.StatusCode}
+		if fields, ok := e.Msg.(map[string]string); ok {
+			problem.Fields = fields
+		} else if msg, ok := e.Msg.(string); ok {
+			problem.Detail = msg
+		}
+	case InfraError:
+		problem = ProblemError{
+			Title:  "Service Unavailable",
+			Status: http.StatusServiceUnavailable,
+			Detail: "service temporarily unavailable",
+		}
+		slog.Error("Infrastructure error", "service", e.ServiceName, "msg", e.Msg, "path", r.URL.Path, "request", requestID)
+	default:
+		problem = ProblemError{
+			Title:  "Internal Server Error",
+			Status: http.StatusInternalServerError,
+			Detail: "internal server error",
+		}
+		slog.Error("Unknown error", "err", err.Error(), "path", r.URL.Path, "request", requestID)
+	}
+	problem.Instance = r.URL.Path
+	problem.Request = requestID
+
+	if wantsProblemJSON(r) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(problem.Status)
+		_ = json.NewEncoder(w).Encode(problem)
+		return
+	}
+
+	msg := any(problem.Detail)
+	if problem.Fields != nil {
+		msg = problem.Fields
+	}
+	_ = writeJSON(w, problem.Status, errResponse{
+		StatusCode: problem.Status,
+		Msg:        msg,
+		Request:    requestID,
+	})
+}
+
+// writeErrors оборачивает next: если next (обработчик или любой middleware
+// ниже по цепочке, например RateLimiter, отклоняющий запрос без вызова next)
+// возвращает ошибку, она тут же записывается в w через WriteError, и дальше по
+// цепочке распространяется уже nil. Используется AccessLog, оборачивая им весь
+// следующий за ним middleware, чтобы его statusWriter видел реальную запись, а
+// не голую ошибку после того, как Chain уже вернул управление.
+func writeErrors(next APIFunc) APIFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if err := next(w, r); err != nil {
+			WriteError(w, r, err)
+		}
+		return nil
+	}
+}
+
+// wantsProblemJSON сообщает, просит ли клиент ответ в формате application/problem+json.
+func wantsProblemJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/problem+json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+type ctxKey int
+
+const requestIDKey ctxKey = iota
+
+// requestIDHeader - имя заголовка, используемого для передачи идентификатора запроса.
+const requestIDHeader = "X-Request-ID"
+
+// WithRequestID оборачивает http.Handler и присваивает каждому запросу идентификатор,
+// уважая входящий заголовок X-Request-ID, если он присутствует. Идентификатор
+// сохраняется в контексте запроса и дублируется в заголовке ответа.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext возвращает идентификатор запроса, сохранённый WithRequestID,
+// либо пустую строку, если он отсутствует в контексте.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("fallback-%p", &b)
+	}
+	return fmt.Sprintf("%x", b)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffConfig описывает экспоненциальный backoff с джиттером, используемый
+// Client между повторными попытками.
+type BackoffConfig struct {
+	Base     time.Duration
+	Max      time.Duration
+	Attempts int
+}
+
+// DefaultBackoff - параметры backoff, разумные по умолчанию для большинства
+// инфраструктурных вызовов.
+var DefaultBackoff = BackoffConfig{
+	Base:     100 * time.Millisecond,
+	Max:      2 * time.Second,
+	Attempts: 3,
+}
+
+// delay возвращает задержку перед попыткой attempt (считая с 1): полный
+// джиттер в диапазоне [0, min(Max, Base*2^attempt)).
+func (b BackoffConfig) delay(attempt int) time.Duration {
+	d := float64(b.Base) * math.Pow(2, float64(attempt))
+	if d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	return time.Duration(rand.Float64() * d)
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker защищает вызывающую сторону от повторяющихся сбоев
+// нижестоящего сервиса: после FailureThreshold подряд неудач в пределах
+// Window переходит в открытое состояние и отклоняет вызовы в течение
+// Cooldown, после чего пропускает одну пробную попытку (half-open).
+// Успешная проба замыкает цепь обратно.
+type CircuitBreaker struct {
+	FailureThreshold int
+	Window           time.Duration
+	Cooldown         time.Duration
+
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probing     bool
+}
+
+// NewCircuitBreaker создаёт замкнутый CircuitBreaker с заданными порогами.
+func NewCircuitBreaker(failureThreshold int, window, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Window:           window,
+		Cooldown:         cooldown,
+	}
+}
+
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if time.Since(cb.openedAt) < cb.Cooldown {
+			return false
+		}
+		cb.state = breakerHalfOpen
+		cb.probing = false
+		return true
+	case breakerHalfOpen:
+		if !cb.probing {
+			return false
+		}
+		cb.probing = false
+		return true
+	default:
+		return true
+	}
+}
+
+func (cb *CircuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.state = breakerClosed
+	cb.failures = 0
+}
+
+func (cb *CircuitBreaker) recordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == breakerHalfOpen {
+		cb.open()
+		return
+	}
+
+	now := time.Now()
+	if cb.windowStart.IsZero() || now.Sub(cb.windowStart) > cb.Window {
+		cb.windowStart = now
+		cb.failures = 0
+	}
+	cb.failures++
+	if cb.failures >= cb.FailureThreshold {
+		cb.open()
+	}
+}
+
+func (cb *CircuitBreaker) open() {
+	cb.state = breakerOpen
+	cb.openedAt = time.Now()
+	cb.failures = 0
+	cb.probing = false
+}
+
+// Client оборачивает вызовы к внешнему сервису ServiceName повторными
+// попытками с экспоненциальным backoff и circuit breaker, отображая итоговый
+// сбой в InfraError.
+type Client struct {
+	ServiceName string
+	Backoff     BackoffConfig
+	Breaker     *CircuitBreaker
+}
+
+// NewClient создаёт Client для serviceName с backoff и порогами размыкателя
+// по умолчанию.
+func NewClient(serviceName string) *Client {
+	return &Client{
+		ServiceName: serviceName,
+		Backoff:     DefaultBackoff,
+		Breaker:     NewCircuitBreaker(5, 30*time.Second, 10*time.Second),
+	}
+}
+
+// Do выполняет fn с учётом backoff и circuit breaker клиента. Если
+// размыкатель разомкнут, fn не вызывается и Do сразу возвращает
+// NewInfraError(c.ServiceName, "circuit open"). Иначе fn повторяется до
+// Backoff.Attempts раз; если все попытки неудачны, возвращает InfraError с
+// последней ошибкой, и размыкатель учитывает сбой.
+func (c *Client) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !c.Breaker.allow() {
+		return NewInfraError(c.ServiceName, "circuit open")
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < c.Backoff.Attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.Backoff.delay(attempt)):
+			case <-ctx.Done():
+				return NewInfraError(c.ServiceName, ctx.Err().Error())
+			}
+		}
+
+		if err := fn(ctx); err != nil {
+			lastErr = err
+			continue
+		}
+
+		c.Breaker.recordSuccess()
+		return nil
+	}
+
+	c.Breaker.recordFailure()
+	return NewInfraError(c.ServiceName, lastErr.Error())
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker(3, time.Minute, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if !cb.allow() {
+			t.Fatalf("allow() = false before threshold reached (failure %d)", i)
+		}
+		cb.recordFailure()
+	}
+	if cb.state != breakerClosed {
+		t.Fatalf("state = %v, want breakerClosed before threshold", cb.state)
+	}
+
+	if !cb.allow() {
+		t.Fatal("allow() = false before threshold reached (failure 2)")
+	}
+	cb.recordFailure()
+	if cb.state != breakerOpen {
+		t.Fatalf("state = %v, want breakerOpen at threshold", cb.state)
+	}
+	if cb.allow() {
+		t.Fatal("allow() = true while breaker is open")
+	}
+}
+
+func TestCircuitBreakerResetsFailuresOutsideWindow(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Millisecond, time.Minute)
+
+	cb.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+	cb.recordFailure()
+
+	if cb.state != breakerClosed {
+		t.Fatalf("state = %v, want breakerClosed when failures fall outside Window", cb.state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeCloses(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, time.Millisecond)
+
+	cb.recordFailure()
+	if cb.state != breakerOpen {
+		t.Fatalf("state = %v, want breakerOpen", cb.state)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("allow() = false after Cooldown elapsed, want a probe to be let through")
+	}
+	if cb.state != breakerHalfOpen {
+		t.Fatalf("state = %v, want breakerHalfOpen after cooldown", cb.state)
+	}
+	if cb.allow() {
+		t.Fatal("allow() = true for a second call before the probe admitted by the cooldown-elapsed call recorded a result, want exactly one admission per half-open probe")
+	}
+
+	cb.recordSuccess()
+	if cb.state != breakerClosed {
+		t.Fatalf("state = %v, want breakerClosed after a successful probe", cb.state)
+	}
+	if !cb.allow() {
+		t.Fatal("allow() = false after breaker closed")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeReopensOnFailure(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, time.Millisecond)
+
+	cb.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("allow() = false after Cooldown elapsed, want a probe to be let through")
+	}
+
+	cb.recordFailure()
+	if cb.state != breakerOpen {
+		t.Fatalf("state = %v, want breakerOpen after a failed probe", cb.state)
+	}
+	if cb.allow() {
+		t.Fatal("allow() = true immediately after a failed probe reopened the breaker")
+	}
+}
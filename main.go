@@ -1,104 +1,56 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 )
 
-// ApiError представляет ошибки бизнес-логики
-type ApiError struct {
-	StatusCode int `json:"statusCode"`
-	Msg        any `json:"msg"`
+// CreateUserRequest - пример тела запроса, проверяемого Decode через Validator.
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
 }
 
-func (e ApiError) Error() string {
-	return fmt.Sprintf("%d: %v", e.StatusCode, e.Msg)
-}
-
-func NewApiError(statusCode int, err error) ApiError {
-	return ApiError{
-		StatusCode: statusCode,
-		Msg:        err.Error(),
-	}
-}
-
-// InfraError представляет ошибки инфраструктуры
-type InfraError struct {
-	ServiceName string
-	Msg         string
-}
-
-func (e InfraError) Error() string {
-	return fmt.Sprintf("infrastructure error with service %s: %s", e.ServiceName, e.Msg)
-}
-
-func NewInfraError(serviceName, msg string) InfraError {
-	return InfraError{
-		ServiceName: serviceName,
-		Msg:         msg,
+func (req CreateUserRequest) Validate() map[string]string {
+	errs := map[string]string{}
+	if req.Username == "" {
+		errs["username"] = "username is required"
 	}
-}
-
-// APIFunc - обработчик API, который может вернуть ошибку
-type APIFunc func(w http.ResponseWriter, r *http.Request) error
-
-// Make оборачивает APIFunc для обработки ошибок
-func Make(h APIFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if err := h(w, r); err != nil {
-			switch e := err.(type) {
-			case ApiError:
-				// Возвращаем ошибку бизнес-логики
-				writeJSON(w, e.StatusCode, e)
-			case InfraError:
-				// Возвращаем инфраструктурную ошибку с кодом 503
-				errResp := map[string]any{
-					"statusCode": http.StatusServiceUnavailable,
-					"msg":        "service temporarily unavailable",
-				}
-				writeJSON(w, http.StatusServiceUnavailable, errResp)
-				// Логируем инфраструктурную ошибку с подробностями
-				slog.Error("Infrastructure error", "service", e.ServiceName, "msg", e.Msg, "path", r.URL.Path)
-			default:
-				// Общая ошибка сервера
-				errResp := map[string]any{
-					"statusCode": http.StatusInternalServerError,
-					"msg":        "internal server error",
-				}
-				writeJSON(w, http.StatusInternalServerError, errResp)
-				slog.Error("Unknown error", "err", err.Error(), "path", r.URL.Path)
-			}
-		}
+	if !strings.Contains(req.Email, "@") {
+		errs["email"] = "email is invalid"
 	}
-}
-
-func writeJSON(w http.ResponseWriter, status int, v any) error {
-	w.WriteHeader(status)
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(v)
+	return errs
 }
 
 // Пример обработчика бизнес-логики
 func validationErrorHandler(w http.ResponseWriter, r *http.Request) error {
-	errors := map[string]string{
-		"username": "username is required",
-		"email":    "email is invalid",
-	}
-	return ApiError{
-		StatusCode: http.StatusUnprocessableEntity,
-		Msg:        errors,
+	var req CreateUserRequest
+	if err := Decode(r, &req); err != nil {
+		return err
 	}
+	return writeJSON(w, http.StatusOK, req)
 }
 
+var (
+	dbClient    = NewClient("Database")
+	cacheClient = NewClient("Cache")
+)
+
 func dbErrorHandler(w http.ResponseWriter, r *http.Request) error {
-	return NewInfraError("Database", "failed to connect to database")
+	return dbClient.Do(r.Context(), func(ctx context.Context) error {
+		return errors.New("failed to connect to database")
+	})
 }
 
 func cacheErrorHandler(w http.ResponseWriter, r *http.Request) error {
-	return NewInfraError("Cache", "failed to connect to Redis")
+	return cacheClient.Do(r.Context(), func(ctx context.Context) error {
+		return errors.New("failed to connect to Redis")
+	})
 }
 
 type Response struct {
@@ -110,17 +62,39 @@ func helloHandler(w http.ResponseWriter, r *http.Request) error {
 	return writeJSON(w, http.StatusOK, response)
 }
 
+// HeroResponse - пример ответа, демонстрирующий параметр пути ":id".
+type HeroResponse struct {
+	ID string `json:"id"`
+}
+
+func heroHandler(w http.ResponseWriter, r *http.Request) error {
+	response := HeroResponse{ID: Params(r).ByName("id")}
+	return writeJSON(w, http.StatusOK, response)
+}
+
 func main() {
-	// Регистрация маршрутов с обработчиками
-	http.HandleFunc("/hello", Make(helloHandler))
-	http.HandleFunc("/validationerror", Make(validationErrorHandler))
-	http.HandleFunc("/dberror", Make(dbErrorHandler))
-	http.HandleFunc("/cacheerror", Make(cacheErrorHandler))
+	router := NewRouter()
+	limiter := NewRateLimiter(10, 20)
+	router.Use(AccessLog, Recover, limiter.Middleware(), Timeout(5*time.Second))
+
+	v1 := router.Group("/v1")
+
+	v1.GET("/hello", helloHandler)
+	v1.POST("/validationerror", validationErrorHandler)
+	v1.GET("/dberror", dbErrorHandler)
+	v1.GET("/cacheerror", cacheErrorHandler)
+	v1.GET("/heroes/:id", heroHandler)
 
 	port := 4009
-	fmt.Printf("Starting server on port %d...\n", port)
-	err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-	if err != nil {
+	server := NewServer(fmt.Sprintf(":%d", port), router)
+	server.AddHealthCheck("database", func(ctx context.Context) error {
+		return dbClient.Do(ctx, func(ctx context.Context) error { return nil })
+	})
+	server.AddHealthCheck("cache", func(ctx context.Context) error {
+		return cacheClient.Do(ctx, func(ctx context.Context) error { return nil })
+	})
+
+	if err := server.Run(context.Background()); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Middleware оборачивает APIFunc дополнительным поведением (логирование,
+// восстановление после паники, ограничение частоты запросов и т.п.).
+type Middleware func(APIFunc) APIFunc
+
+// Chain комбинирует middleware в один Middleware. Middleware выполняются в
+// порядке перечисления: первый в списке первым получает запрос и последним
+// видит ответ.
+func Chain(mws ...Middleware) Middleware {
+	return func(final APIFunc) APIFunc {
+		for i := len(mws) - 1; i >= 0; i-- {
+			final = mws[i](final)
+		}
+		return final
+	}
+}
+
+// Recover перехватывает панику внутри обработчика, логирует стек через slog
+// и сразу записывает её как 500 ApiError в w, вместо того чтобы вернуть
+// ошибку наверх: паника обрывает стек раньше, чем до неё дошла бы обычная
+// обработка возвращённой ошибки, так что писать ответ нужно здесь же. Должен
+// регистрироваться через Use после AccessLog, чтобы тот видел реальный статус
+// через свой statusWriter и при панике тоже.
+func Recover(next APIFunc) APIFunc {
+	return func(w http.ResponseWriter, r *http.Request) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				buf := make([]byte, 4096)
+				n := runtime.Stack(buf, false)
+				slog.Error("panic recovered", "err", rec, "stack", string(buf[:n]), "path", r.URL.Path)
+				WriteError(w, r, ApiError{StatusCode: http.StatusInternalServerError, Msg: "internal server error"})
+			}
+		}()
+		return next(w, r)
+	}
+}
+
+// statusWriter оборачивает http.ResponseWriter, чтобы AccessLog мог узнать
+// итоговый статус и размер ответа.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += n
+	return n, err
+}
+
+// AccessLog пишет структурированную запись о каждом запросе: метод, путь,
+// статус, длительность, идентификатор запроса и число записанных байт.
+// Оборачивает next в writeErrors, чтобы статус в логе совпадал с тем, что
+// реально ушло клиенту, даже если ответ с ошибкой пишет не сам обработчик, а
+// какой-то middleware ниже по цепочке (например, RateLimiter, отклоняющий
+// запрос без вызова next). Должен регистрироваться через Use раньше Recover
+// (т.е. оборачивать его), иначе при панике выполнение обрывается до вызова
+// slog.Info и строка лога не пишется вовсе.
+func AccessLog(next APIFunc) APIFunc {
+	next = writeErrors(next)
+	return func(w http.ResponseWriter, r *http.Request) error {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		err := next(sw, r)
+		slog.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"duration", time.Since(start),
+			"bytes", sw.bytes,
+			"request", RequestIDFromContext(r.Context()),
+		)
+		return err
+	}
+}
+
+// Timeout отменяет контекст запроса по истечении d, позволяя обработчику и
+// вызываемым им зависимостям прервать работу вовремя.
+func Timeout(d time.Duration) Middleware {
+	return func(next APIFunc) APIFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			return next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// bucketStaleAfter - как долго bucket хранится без обращений, прежде чем
+// lazy-чистка сочтёт его устаревшим и удалит.
+const bucketStaleAfter = 10 * time.Minute
+
+// sweepInterval - не чаще какого интервала RateLimiter пересматривает карту
+// buckets на предмет устаревших записей.
+const sweepInterval = time.Minute
+
+// RateLimiter - token-bucket ограничитель частоты запросов, учитываемый
+// по ключу (обычно IP клиента).
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	rps       float64
+	burst     int
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewRateLimiter создаёт RateLimiter, допускающий rps запросов в секунду на
+// ключ, с возможностью накопить до burst запросов впрок.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		rps:     rps,
+		burst:   burst,
+	}
+}
+
+func (rl *RateLimiter) allow(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		rl.buckets[key] = &tokenBucket{tokens: float64(rl.burst) - 1, lastSeen: now}
+		return true
+	}
+
+	b.tokens += now.Sub(b.lastSeen).Seconds() * rl.rps
+	if b.tokens > float64(rl.burst) {
+		b.tokens = float64(rl.burst)
+	}
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep удаляет bucket'ы клиентов, не обращавшихся дольше bucketStaleAfter,
+// чтобы карта не росла без ограничений под трафиком от множества IP.
+// Вызывается не чаще sweepInterval и предполагает, что rl.mu уже захвачен.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < sweepInterval {
+		return
+	}
+	rl.lastSweep = now
+	for key, b := range rl.buckets {
+		if now.Sub(b.lastSeen) > bucketStaleAfter {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// Middleware возвращает Middleware, отклоняющий запросы, превышающие
+// настроенную частоту, с ответом 429 ApiError.
+func (rl *RateLimiter) Middleware() Middleware {
+	return func(next APIFunc) APIFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			key := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+				key = host
+			}
+			if !rl.allow(key) {
+				return ApiError{StatusCode: http.StatusTooManyRequests, Msg: "rate limit exceeded"}
+			}
+			return next(w, r)
+		}
+	}
+}
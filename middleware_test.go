@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureAccessLog runs a single request through rt and returns the slog
+// text emitted by AccessLog for that request.
+func captureAccessLog(t *testing.T, rt *Router, req *http.Request) string {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+	return buf.String()
+}
+
+func TestAccessLogReflectsRateLimiterRejection(t *testing.T) {
+	rt := NewRouter()
+	limiter := NewRateLimiter(1, 1)
+	rt.Use(AccessLog, Recover, limiter.Middleware())
+	rt.GET("/limited", func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, http.StatusOK, nil)
+	})
+
+	// Burn the single allowed token so the next request is rejected by
+	// RateLimiter.Middleware() without ever calling the handler.
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	captureAccessLog(t, rt, req)
+
+	rejected := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	rejected.RemoteAddr = "10.0.0.1:1234"
+	out := captureAccessLog(t, rt, rejected)
+
+	if !strings.Contains(out, "status=429") {
+		t.Fatalf("access log = %q, want it to report status=429 for the rate-limited request", out)
+	}
+}
+
+func TestAccessLogReflectsHandlerAPIError(t *testing.T) {
+	rt := NewRouter()
+	rt.Use(AccessLog, Recover)
+	rt.GET("/teapot", func(w http.ResponseWriter, r *http.Request) error {
+		return ApiError{StatusCode: http.StatusTeapot, Msg: "nope"}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/teapot", nil)
+	out := captureAccessLog(t, rt, req)
+
+	if !strings.Contains(out, "status=418") {
+		t.Fatalf("access log = %q, want it to report status=418", out)
+	}
+}
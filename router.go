@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Router - простой HTTP-роутер с маршрутизацией по методу, параметрами пути
+// (сегмент вида :id) и вложенными группами маршрутов (router.Group("/v1")).
+type Router struct {
+	prefix      string
+	routes      *[]routeEntry
+	middlewares *[]Middleware
+}
+
+type routeEntry struct {
+	method   string
+	segments []string
+	handler  http.HandlerFunc
+}
+
+// NewRouter создаёт пустой Router, готовый к регистрации маршрутов.
+func NewRouter() *Router {
+	routes := make([]routeEntry, 0)
+	mws := make([]Middleware, 0)
+	return &Router{routes: &routes, middlewares: &mws}
+}
+
+// Group возвращает Router, разделяющий таблицу маршрутов и middleware с rt,
+// но добавляющий prefix ко всем маршрутам, зарегистрированным через него.
+func (rt *Router) Group(prefix string) *Router {
+	return &Router{prefix: joinPath(rt.prefix, prefix), routes: rt.routes, middlewares: rt.middlewares}
+}
+
+// Use добавляет middleware, применяемые ко всем маршрутам, зарегистрированным
+// после этого вызова через rt и его группы.
+func (rt *Router) Use(mws ...Middleware) {
+	*rt.middlewares = append(*rt.middlewares, mws...)
+}
+
+// Handle регистрирует обработчик h для пары (method, pattern), пропуская его
+// через цепочку middleware, накопленную к этому моменту. Запись ответа при
+// ошибке, возвращённой обработчиком или любым middleware цепочки, обеспечивает
+// AccessLog (см. writeErrors); Make ниже — лишь запасной вариант для маршрутов,
+// зарегистрированных без AccessLog.
+func (rt *Router) Handle(method, pattern string, h APIFunc) {
+	full := joinPath(rt.prefix, pattern)
+	h = Chain(*rt.middlewares...)(h)
+	*rt.routes = append(*rt.routes, routeEntry{
+		method:   method,
+		segments: splitPath(full),
+		handler:  Make(h),
+	})
+}
+
+func (rt *Router) GET(pattern string, h APIFunc)    { rt.Handle(http.MethodGet, pattern, h) }
+func (rt *Router) POST(pattern string, h APIFunc)   { rt.Handle(http.MethodPost, pattern, h) }
+func (rt *Router) PUT(pattern string, h APIFunc)    { rt.Handle(http.MethodPut, pattern, h) }
+func (rt *Router) DELETE(pattern string, h APIFunc) { rt.Handle(http.MethodDelete, pattern, h) }
+
+// ServeHTTP реализует http.Handler, находя первый зарегистрированный маршрут,
+// совпадающий по методу и сегментам пути.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reqSegments := splitPath(r.URL.Path)
+	for _, route := range *rt.routes {
+		if route.method != r.Method {
+			continue
+		}
+		params, ok := matchSegments(route.segments, reqSegments)
+		if !ok {
+			continue
+		}
+		if len(params) > 0 {
+			r = r.WithContext(context.WithValue(r.Context(), paramsCtxKey, params))
+		}
+		route.handler(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func matchSegments(pattern, actual []string) (RouteParams, bool) {
+	if len(pattern) != len(actual) {
+		return nil, false
+	}
+	var params RouteParams
+	for i, seg := range pattern {
+		if strings.HasPrefix(seg, ":") {
+			if params == nil {
+				params = make(RouteParams)
+			}
+			params[seg[1:]] = actual[i]
+			continue
+		}
+		if seg != actual[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func joinPath(a, b string) string {
+	a = strings.TrimSuffix(a, "/")
+	b = strings.TrimPrefix(b, "/")
+	if a == "" {
+		return "/" + b
+	}
+	if b == "" {
+		return a
+	}
+	return a + "/" + b
+}
+
+type routeCtxKey int
+
+const paramsCtxKey routeCtxKey = 0
+
+// RouteParams хранит значения параметров пути, извлечённые Router из совпавшего
+// маршрута.
+type RouteParams map[string]string
+
+func (p RouteParams) ByName(name string) string {
+	return p[name]
+}
+
+// Params возвращает параметры пути текущего запроса. Если запрос не проходил
+// через Router или маршрут не содержал параметров, ByName вернёт "".
+func Params(r *http.Request) RouteParams {
+	p, _ := r.Context().Value(paramsCtxKey).(RouteParams)
+	return p
+}
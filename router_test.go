@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchSegments(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		actual  string
+		wantOK  bool
+		want    RouteParams
+	}{
+		{name: "exact match", pattern: "v1/hello", actual: "v1/hello", wantOK: true, want: nil},
+		{name: "single param", pattern: "v1/heroes/:id", actual: "v1/heroes/42", wantOK: true, want: RouteParams{"id": "42"}},
+		{name: "multiple params", pattern: "v1/:kind/:id", actual: "v1/heroes/42", wantOK: true, want: RouteParams{"kind": "heroes", "id": "42"}},
+		{name: "literal mismatch", pattern: "v1/heroes/:id", actual: "v1/villains/42", wantOK: false},
+		{name: "length mismatch", pattern: "v1/heroes/:id", actual: "v1/heroes", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params, ok := matchSegments(splitPath(tc.pattern), splitPath(tc.actual))
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if len(params) != len(tc.want) {
+				t.Fatalf("params = %v, want %v", params, tc.want)
+			}
+			for k, v := range tc.want {
+				if params[k] != v {
+					t.Fatalf("params[%q] = %q, want %q", k, params[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestRouterServeHTTPRoutesByMethodAndPath(t *testing.T) {
+	rt := NewRouter()
+	rt.GET("/heroes/:id", func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, http.StatusOK, HeroResponse{ID: Params(r).ByName("id")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/heroes/42", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if body := rec.Body.String(); body != `{"id":"42"}`+"\n" {
+		t.Fatalf("body = %q", body)
+	}
+}
+
+func TestRouterServeHTTPNotFoundOnMethodMismatch(t *testing.T) {
+	rt := NewRouter()
+	rt.GET("/heroes/:id", func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, http.StatusOK, nil)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/heroes/42", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRouterGroupPrefixesRoutes(t *testing.T) {
+	rt := NewRouter()
+	v1 := rt.Group("/v1")
+	v1.GET("/hello", func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, http.StatusOK, nil)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/hello", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
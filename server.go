@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// HealthCheck - проверка готовности зависимости, вызываемая /readyz.
+type HealthCheck func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// Server оборачивает http.Server конфигурацией таймаутов, набором проверок
+// готовности и управляемым жизненным циклом (запуск + изящное завершение).
+type Server struct {
+	Addr            string
+	Handler         http.Handler
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+
+	checks []namedCheck
+}
+
+// NewServer создаёт Server с разумными значениями таймаутов по умолчанию.
+func NewServer(addr string, handler http.Handler) *Server {
+	return &Server{
+		Addr:            addr,
+		Handler:         handler,
+		ReadTimeout:     5 * time.Second,
+		WriteTimeout:    10 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// AddHealthCheck регистрирует проверку готовности под именем name,
+// сообщаемую /readyz.
+func (s *Server) AddHealthCheck(name string, check HealthCheck) {
+	s.checks = append(s.checks, namedCheck{name: name, check: check})
+}
+
+// Run запускает HTTP-сервер и блокируется до получения SIGINT/SIGTERM либо
+// отмены ctx, после чего изящно завершает работу через http.Server.Shutdown,
+// дожидаясь завершения обрабатываемых запросов в пределах ShutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/", s.Handler)
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+
+	httpServer := &http.Server{
+		Addr:         s.Addr,
+		Handler:      WithRequestID(mux),
+		ReadTimeout:  s.ReadTimeout,
+		WriteTimeout: s.WriteTimeout,
+		IdleTimeout:  s.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Printf("Starting server on %s...\n", s.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	_ = writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	var failed []string
+	for _, c := range s.checks {
+		if err := c.check(r.Context()); err != nil {
+			failed = append(failed, c.name)
+			slog.Error("readiness check failed", "check", c.name, "err", err)
+		}
+	}
+	if len(failed) > 0 {
+		_ = writeJSON(w, http.StatusServiceUnavailable, errResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Msg:        failed,
+			Request:    RequestIDFromContext(r.Context()),
+		})
+		return
+	}
+	_ = writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}